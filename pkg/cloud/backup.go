@@ -0,0 +1,250 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"k8s.io/klog/v2"
+)
+
+// DefaultBackupVaultName is the AWS Backup vault the driver provisions snapshots into when the
+// storage class does not request a specific one. ensureBackupVault creates it on first use if it
+// does not already exist, so clusters don't need to pre-provision a vault just to take snapshots.
+const DefaultBackupVaultName = "aws-efs-csi-driver"
+
+type Snapshot struct {
+	SnapshotId         string
+	SourceFileSystemId string
+	CreationTime       time.Time
+	SizeBytes          int64
+	ReadyToUse         bool
+}
+
+// BackupOptions holds the parameters needed to start an AWS Backup job for an EFS file system.
+type BackupOptions struct {
+	// VaultName is the backup vault the recovery point is stored in. Defaults to DefaultBackupVaultName.
+	VaultName string
+	// IamRoleArn is the role AWS Backup assumes to read the file system.
+	IamRoleArn string
+	Tags       map[string]string
+}
+
+// Backup abstracts the AWS Backup client (https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/backup)
+type Backup interface {
+	StartBackupJob(context.Context, *backup.StartBackupJobInput, ...func(*backup.Options)) (*backup.StartBackupJobOutput, error)
+	DescribeBackupJob(context.Context, *backup.DescribeBackupJobInput, ...func(*backup.Options)) (*backup.DescribeBackupJobOutput, error)
+	ListRecoveryPointsByResource(context.Context, *backup.ListRecoveryPointsByResourceInput, ...func(*backup.Options)) (*backup.ListRecoveryPointsByResourceOutput, error)
+	DeleteRecoveryPoint(context.Context, *backup.DeleteRecoveryPointInput, ...func(*backup.Options)) (*backup.DeleteRecoveryPointOutput, error)
+	DescribeBackupVault(context.Context, *backup.DescribeBackupVaultInput, ...func(*backup.Options)) (*backup.DescribeBackupVaultOutput, error)
+	CreateBackupVault(context.Context, *backup.CreateBackupVaultInput, ...func(*backup.Options)) (*backup.CreateBackupVaultOutput, error)
+	StartRestoreJob(context.Context, *backup.StartRestoreJobInput, ...func(*backup.Options)) (*backup.StartRestoreJobOutput, error)
+	DescribeRestoreJob(context.Context, *backup.DescribeRestoreJobInput, ...func(*backup.Options)) (*backup.DescribeRestoreJobOutput, error)
+}
+
+func createBackupClient(cfg aws.Config) Backup {
+	return backup.NewFromConfig(cfg)
+}
+
+func (c *cloud) CreateSnapshot(ctx context.Context, clientToken, fileSystemId string, backupOpts *BackupOptions) (snapshot *Snapshot, err error) {
+	vaultName := backupOpts.VaultName
+	if vaultName == "" {
+		vaultName = DefaultBackupVaultName
+	}
+
+	if err := c.ensureBackupVault(ctx, vaultName); err != nil {
+		return nil, fmt.Errorf("Failed to ensure backup vault %v exists: %v", vaultName, err)
+	}
+
+	resourceArn := c.fileSystemArn(fileSystemId)
+	startBackupJobInput := &backup.StartBackupJobInput{
+		BackupVaultName:   &vaultName,
+		ResourceArn:       &resourceArn,
+		IamRoleArn:        &backupOpts.IamRoleArn,
+		IdempotencyToken:  &clientToken,
+		RecoveryPointTags: parseBackupTags(backupOpts.Tags),
+	}
+
+	klog.V(5).Infof("Calling StartBackupJob with input: %+v", *startBackupJobInput)
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+	res, err := c.backup.StartBackupJob(opCtx, startBackupJobInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
+		}
+		return nil, fmt.Errorf("Failed to start backup job for file system %v: %v", fileSystemId, err)
+	}
+
+	return &Snapshot{
+		SnapshotId:         aws.ToString(res.RecoveryPointArn),
+		SourceFileSystemId: fileSystemId,
+		ReadyToUse:         false,
+	}, nil
+}
+
+func (c *cloud) DeleteSnapshot(ctx context.Context, snapshotId string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	deleteRecoveryPointInput := &backup.DeleteRecoveryPointInput{
+		BackupVaultName:  aws.String(DefaultBackupVaultName),
+		RecoveryPointArn: &snapshotId,
+	}
+	_, err = c.backup.DeleteRecoveryPoint(ctx, deleteRecoveryPointInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return ErrAccessDenied
+		}
+		if isBackupResourceNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("Failed to delete recovery point %v: %v", snapshotId, err)
+	}
+
+	return nil
+}
+
+func (c *cloud) ListSnapshots(ctx context.Context, fileSystemId string) (snapshots []*Snapshot, err error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	resourceArn := c.fileSystemArn(fileSystemId)
+	listRecoveryPointsInput := &backup.ListRecoveryPointsByResourceInput{ResourceArn: &resourceArn}
+
+	klog.V(5).Infof("Calling ListRecoveryPointsByResource with input: %+v", *listRecoveryPointsInput)
+	res, err := c.backup.ListRecoveryPointsByResource(ctx, listRecoveryPointsInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
+		}
+		return nil, fmt.Errorf("Failed to list recovery points for file system %v: %v", fileSystemId, err)
+	}
+
+	for _, rp := range res.RecoveryPoints {
+		snapshots = append(snapshots, &Snapshot{
+			SnapshotId:         aws.ToString(rp.RecoveryPointArn),
+			SourceFileSystemId: fileSystemId,
+			CreationTime:       aws.ToTime(rp.CreationDate),
+			SizeBytes:          aws.ToInt64(rp.BackupSizeBytes),
+			ReadyToUse:         rp.Status == types.RecoveryPointStatusCompleted,
+		})
+	}
+
+	return snapshots, nil
+}
+
+func (c *cloud) RestoreFileSystem(ctx context.Context, recoveryPointArn, iamRoleArn string) (fs *FileSystem, err error) {
+	startRestoreJobInput := &backup.StartRestoreJobInput{
+		RecoveryPointArn: &recoveryPointArn,
+		IamRoleArn:       &iamRoleArn,
+		ResourceType:     aws.String("EFS"),
+	}
+
+	klog.V(5).Infof("Calling StartRestoreJob with input: %+v", *startRestoreJobInput)
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	res, err := c.backup.StartRestoreJob(opCtx, startRestoreJobInput)
+	cancel()
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
+		}
+		return nil, fmt.Errorf("Failed to start restore job from recovery point %v: %v", recoveryPointArn, err)
+	}
+
+	restoreJobId := aws.ToString(res.RestoreJobId)
+	fileSystemId, err := c.waitForRestoreJobCompleted(ctx, restoreJobId)
+	if err != nil {
+		return nil, fmt.Errorf("Restore job %v did not complete: %v", restoreJobId, err)
+	}
+
+	return &FileSystem{FileSystemId: fileSystemId}, nil
+}
+
+func (c *cloud) ensureBackupVault(ctx context.Context, vaultName string) error {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	_, err := c.backup.DescribeBackupVault(ctx, &backup.DescribeBackupVaultInput{BackupVaultName: &vaultName})
+	if err == nil {
+		return nil
+	}
+	if !isBackupResourceNotFound(err) {
+		return err
+	}
+
+	klog.V(2).Infof("Backup vault %v does not exist, creating it", vaultName)
+	_, err = c.backup.CreateBackupVault(ctx, &backup.CreateBackupVaultInput{BackupVaultName: &vaultName})
+	if err != nil && !isBackupVaultAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (c *cloud) waitForRestoreJobCompleted(ctx context.Context, restoreJobId string) (fileSystemId string, err error) {
+	err = waitForState(ctx, PollCheckInterval, PollCheckTimeout, string(types.RestoreJobStatusCompleted), func() (string, error) {
+		opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+		defer cancel()
+
+		res, describeErr := c.backup.DescribeRestoreJob(opCtx, &backup.DescribeRestoreJobInput{RestoreJobId: &restoreJobId})
+		if describeErr != nil {
+			return "", describeErr
+		}
+		if res.Status == types.RestoreJobStatusFailed {
+			return "", fmt.Errorf("restore job %v failed: %v", restoreJobId, aws.ToString(res.StatusMessage))
+		}
+		fileSystemId = fileSystemIdFromArn(aws.ToString(res.CreatedResourceArn))
+		return string(res.Status), nil
+	})
+	return fileSystemId, err
+}
+
+// fileSystemArn builds the EFS file system ARN AWS Backup expects as a ResourceArn.
+func (c *cloud) fileSystemArn(fileSystemId string) string {
+	return fmt.Sprintf("arn:aws:elasticfilesystem:%s:%s:file-system/%s", c.metadata.GetRegion(), c.accountId, fileSystemId)
+}
+
+// fileSystemIdFromArn extracts the fs-xxxx suffix from an EFS file system ARN.
+func fileSystemIdFromArn(fileSystemArn string) string {
+	parts := strings.Split(fileSystemArn, "/")
+	return parts[len(parts)-1]
+}
+
+func parseBackupTags(tagMap map[string]string) map[string]string {
+	backupTags := map[string]string{}
+	for k, v := range tagMap {
+		backupTags[k] = v
+	}
+	return backupTags
+}
+
+func isBackupResourceNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
+
+func isBackupVaultAlreadyExists(err error) bool {
+	var alreadyExists *types.AlreadyExistsException
+	return errors.As(err, &alreadyExists)
+}
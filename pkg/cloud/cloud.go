@@ -24,13 +24,15 @@ import (
 	"os"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/ec2metadata"
-	"github.com/aws/aws-sdk-go/aws/request"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithy "github.com/aws/smithy-go"
 	"k8s.io/klog/v2"
 )
 
@@ -38,6 +40,18 @@ const (
 	AccessDeniedException    = "AccessDeniedException"
 	AccessPointAlreadyExists = "AccessPointAlreadyExists"
 	PvcNameTagKey            = "pvcName"
+	// ClusterNameTagKey is the AccessPoint tag key matched by ListAccessPointsFilter.ClusterName.
+	ClusterNameTagKey = "efs.csi.aws.com/cluster"
+
+	// PollCheckInterval is how often to re-check the lifecycle state of a resource being waited on.
+	PollCheckInterval = 5 * time.Second
+	// PollCheckTimeout is the max amount of time to wait for a resource to reach its desired lifecycle state.
+	PollCheckTimeout = 10 * time.Minute
+
+	// operationTimeout bounds a single EFS/STS API call, independent of any waiter's overall deadline.
+	operationTimeout = 30 * time.Second
+	// maxRetryAttempts is passed to the SDK's adaptive retryer for throttling/transient errors.
+	maxRetryAttempts = 5
 )
 
 var (
@@ -48,6 +62,29 @@ var (
 
 type FileSystem struct {
 	FileSystemId string
+	// AvailabilityZoneName is set only for EFS One Zone file systems, and pins the file system (and
+	// its single mount target) to that AZ.
+	AvailabilityZoneName string
+}
+
+// FileSystemOptions holds the parameters needed to provision a new EFS file system.
+type FileSystemOptions struct {
+	// PerformanceMode is one of types.PerformanceModeGeneralPurpose or types.PerformanceModeMaxIo.
+	PerformanceMode string
+	// ThroughputMode is one of types.ThroughputModeBursting or types.ThroughputModeProvisioned.
+	ThroughputMode string
+	// ProvisionedThroughputInMibps is only used when ThroughputMode is types.ThroughputModeProvisioned.
+	ProvisionedThroughputInMibps float64
+	Encrypted                    bool
+	// KmsKeyId is only used when Encrypted is true. If empty, the AWS managed EFS key is used.
+	KmsKeyId string
+	Tags     map[string]string
+}
+
+// MountTargetOptions holds the parameters needed to create a mount target for a file system in a subnet.
+type MountTargetOptions struct {
+	SubnetId       string
+	SecurityGroups []string
 }
 
 type AccessPoint struct {
@@ -58,6 +95,15 @@ type AccessPoint struct {
 	// EFS does not consider capacity while provisioning new file systems or access points
 	CapacityGiB int64
 	PosixUser   *PosixUser
+	Tags        map[string]string
+}
+
+// ListAccessPointsFilter scopes ListAccessPoints to access points tagged for a specific cluster,
+// so the driver's access-point-tag reaper doesn't delete APs that belong to another cluster sharing
+// the same file system.
+type ListAccessPointsFilter struct {
+	TagFilters  map[string]string
+	ClusterName string
 }
 
 type PosixUser struct {
@@ -76,6 +122,9 @@ type AccessPointOptions struct {
 	DirectoryPerms string
 	DirectoryPath  string
 	Tags           map[string]string
+	// AvailabilityZoneName is the StorageClass-requested zone for a One Zone file system. If set, it
+	// must match the file system's own AvailabilityZoneName or CreateAccessPoint rejects the request.
+	AvailabilityZoneName string
 }
 
 type MountTarget struct {
@@ -83,15 +132,28 @@ type MountTarget struct {
 	AZId          string
 	MountTargetId string
 	IPAddress     string
+	// DnsName is <mount-target-id>.<file-system-id>.efs.<region>.amazonaws.com. It contains the
+	// mount target ID, so like IPAddress it changes when the mount target is recreated.
+	DnsName            string
+	NetworkInterfaceId string
+	SubnetId           string
+	VpcId              string
+	OwnerId            string
+	SecurityGroups     []string
 }
 
-// Efs abstracts efs client(https://docs.aws.amazon.com/sdk-for-go/api/service/efs/)
+// Efs abstracts efs client(https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/efs)
 type Efs interface {
-	CreateAccessPointWithContext(aws.Context, *efs.CreateAccessPointInput, ...request.Option) (*efs.CreateAccessPointOutput, error)
-	DeleteAccessPointWithContext(aws.Context, *efs.DeleteAccessPointInput, ...request.Option) (*efs.DeleteAccessPointOutput, error)
-	DescribeAccessPointsWithContext(aws.Context, *efs.DescribeAccessPointsInput, ...request.Option) (*efs.DescribeAccessPointsOutput, error)
-	DescribeFileSystemsWithContext(aws.Context, *efs.DescribeFileSystemsInput, ...request.Option) (*efs.DescribeFileSystemsOutput, error)
-	DescribeMountTargetsWithContext(aws.Context, *efs.DescribeMountTargetsInput, ...request.Option) (*efs.DescribeMountTargetsOutput, error)
+	CreateAccessPoint(context.Context, *efs.CreateAccessPointInput, ...func(*efs.Options)) (*efs.CreateAccessPointOutput, error)
+	DeleteAccessPoint(context.Context, *efs.DeleteAccessPointInput, ...func(*efs.Options)) (*efs.DeleteAccessPointOutput, error)
+	DescribeAccessPoints(context.Context, *efs.DescribeAccessPointsInput, ...func(*efs.Options)) (*efs.DescribeAccessPointsOutput, error)
+	DescribeFileSystems(context.Context, *efs.DescribeFileSystemsInput, ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error)
+	DescribeMountTargets(context.Context, *efs.DescribeMountTargetsInput, ...func(*efs.Options)) (*efs.DescribeMountTargetsOutput, error)
+	CreateFileSystem(context.Context, *efs.CreateFileSystemInput, ...func(*efs.Options)) (*efs.CreateFileSystemOutput, error)
+	DeleteFileSystem(context.Context, *efs.DeleteFileSystemInput, ...func(*efs.Options)) (*efs.DeleteFileSystemOutput, error)
+	CreateMountTarget(context.Context, *efs.CreateMountTargetInput, ...func(*efs.Options)) (*efs.CreateMountTargetOutput, error)
+	DeleteMountTarget(context.Context, *efs.DeleteMountTargetInput, ...func(*efs.Options)) (*efs.DeleteMountTargetOutput, error)
+	DescribeMountTargetSecurityGroups(context.Context, *efs.DescribeMountTargetSecurityGroupsInput, ...func(*efs.Options)) (*efs.DescribeMountTargetSecurityGroupsOutput, error)
 }
 
 type Cloud interface {
@@ -99,18 +161,28 @@ type Cloud interface {
 	CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions, reuseAccessPoint bool) (accessPoint *AccessPoint, err error)
 	DeleteAccessPoint(ctx context.Context, accessPointId string) (err error)
 	DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *AccessPoint, err error)
-	ListAccessPoints(ctx context.Context, fileSystemId string) (accessPoints []*AccessPoint, err error)
+	ListAccessPoints(ctx context.Context, fileSystemId string, filter *ListAccessPointsFilter) (accessPoints []*AccessPoint, err error)
 	DescribeFileSystem(ctx context.Context, fileSystemId string) (fs *FileSystem, err error)
 	DescribeMountTargets(ctx context.Context, fileSystemId, az string) (fs *MountTarget, err error)
+	CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *FileSystemOptions) (fs *FileSystem, err error)
+	DeleteFileSystem(ctx context.Context, fileSystemId string) (err error)
+	CreateMountTarget(ctx context.Context, fileSystemId string, mountTargetOpts *MountTargetOptions) (mountTarget *MountTarget, err error)
+	DeleteMountTarget(ctx context.Context, mountTargetId string) (err error)
+	CreateSnapshot(ctx context.Context, clientToken, fileSystemId string, backupOpts *BackupOptions) (snapshot *Snapshot, err error)
+	DeleteSnapshot(ctx context.Context, snapshotId string) (err error)
+	ListSnapshots(ctx context.Context, fileSystemId string) (snapshots []*Snapshot, err error)
+	RestoreFileSystem(ctx context.Context, recoveryPointArn, iamRoleArn string) (fs *FileSystem, err error)
 }
 
 type cloud struct {
-	metadata MetadataService
-	efs      Efs
+	metadata  MetadataService
+	efs       Efs
+	backup    Backup
+	accountId string
 }
 
 // NewCloud returns a new instance of AWS cloud
-// It panics if session is invalid
+// It returns an error if the AWS config cannot be loaded.
 func NewCloud() (Cloud, error) {
 	return createCloud("")
 }
@@ -122,15 +194,21 @@ func NewCloudWithRole(awsRoleArn string) (Cloud, error) {
 }
 
 func createCloud(awsRoleArn string) (Cloud, error) {
-	sess := session.Must(session.NewSession(&aws.Config{}))
-	svc := ec2metadata.New(sess)
+	ctx := context.Background()
+
+	cfg, err := loadAWSConfig(ctx, awsRoleArn)
+	if err != nil {
+		return nil, fmt.Errorf("could not load AWS config: %v", err)
+	}
+
+	imdsClient := imds.NewFromConfig(cfg)
 	api, err := DefaultKubernetesAPIClient()
 
 	if err != nil && !isDriverBootedInECS() {
 		klog.Warningf("Could not create Kubernetes Client: %v", err)
 	}
 
-	metadataProvider, err := GetNewMetadataProvider(svc, api)
+	metadataProvider, err := GetNewMetadataProvider(imdsClient, api)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating MetadataProvider: %v", err)
@@ -141,22 +219,56 @@ func createCloud(awsRoleArn string) (Cloud, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not get metadata: %v", err)
 	}
+	cfg.Region = metadata.GetRegion()
 
-	efs_client := createEfsClient(awsRoleArn, metadata, sess)
-	klog.V(5).Infof("EFS Client created using the following endpoint: %+v", efs_client.(*efs.EFS).Client.ClientInfo.Endpoint)
+	accountId, err := getAccountId(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine AWS account ID: %v", err)
+	}
 
 	return &cloud{
-		metadata: metadata,
-		efs:      efs_client,
+		metadata:  metadata,
+		efs:       efs.NewFromConfig(cfg),
+		backup:    createBackupClient(cfg),
+		accountId: accountId,
 	}, nil
 }
 
-func createEfsClient(awsRoleArn string, metadata MetadataService, sess *session.Session) Efs {
-	config := aws.NewConfig().WithRegion(metadata.GetRegion())
+// loadAWSConfig loads the default v2 credential/config chain - which resolves IRSA
+// (AWS_WEB_IDENTITY_TOKEN_FILE) and EKS Pod Identity automatically - and layers an adaptive retry
+// policy on top so throttling from EFS/STS/Backup is retried with backoff instead of surfacing
+// immediately to the CSI caller. If awsRoleArn is set, credentials are further scoped to that role.
+func loadAWSConfig(ctx context.Context, awsRoleArn string) (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRetryer(func() aws.Retryer {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+					so.MaxAttempts = maxRetryAttempts
+				})
+			})
+		}),
+	)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
 	if awsRoleArn != "" {
-		config = config.WithCredentials(stscreds.NewCredentials(sess, awsRoleArn))
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, awsRoleArn))
 	}
-	return efs.New(session.Must(session.NewSession(config)))
+
+	return cfg, nil
+}
+
+func getAccountId(ctx context.Context, cfg aws.Config) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	res, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(res.Account), nil
 }
 
 func (c *cloud) GetMetadata() MetadataService {
@@ -164,6 +276,16 @@ func (c *cloud) GetMetadata() MetadataService {
 }
 
 func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions, reuseAccessPoint bool) (accessPoint *AccessPoint, err error) {
+	if accessPointOpts.AvailabilityZoneName != "" {
+		fs, err := c.DescribeFileSystem(ctx, accessPointOpts.FileSystemId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe file system %v: %v", accessPointOpts.FileSystemId, err)
+		}
+		if fs.AvailabilityZoneName != accessPointOpts.AvailabilityZoneName {
+			return nil, fmt.Errorf("file system %v is in AZ %q, requested AZ %q cannot be satisfied", accessPointOpts.FileSystemId, fs.AvailabilityZoneName, accessPointOpts.AvailabilityZoneName)
+		}
+	}
+
 	efsTags := parseEfsTags(accessPointOpts.Tags)
 
 	//if reuseAccessPoint is true, check for AP with same Root Directory exists in efs
@@ -186,12 +308,12 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 	createAPInput := &efs.CreateAccessPointInput{
 		ClientToken:  &clientToken,
 		FileSystemId: &accessPointOpts.FileSystemId,
-		PosixUser: &efs.PosixUser{
+		PosixUser: &types.PosixUser{
 			Gid: &accessPointOpts.Gid,
 			Uid: &accessPointOpts.Uid,
 		},
-		RootDirectory: &efs.RootDirectory{
-			CreationInfo: &efs.CreationInfo{
+		RootDirectory: &types.RootDirectory{
+			CreationInfo: &types.CreationInfo{
 				OwnerGid:    &accessPointOpts.Gid,
 				OwnerUid:    &accessPointOpts.Uid,
 				Permissions: &accessPointOpts.DirectoryPerms,
@@ -202,7 +324,9 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 	}
 
 	klog.V(5).Infof("Calling Create AP with input: %+v", *createAPInput)
-	res, err := c.efs.CreateAccessPointWithContext(ctx, createAPInput)
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+	res, err := c.efs.CreateAccessPoint(opCtx, createAPInput)
 	if err != nil {
 		if isAccessDenied(err) {
 			return nil, ErrAccessDenied
@@ -219,8 +343,11 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 }
 
 func (c *cloud) DeleteAccessPoint(ctx context.Context, accessPointId string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
 	deleteAccessPointInput := &efs.DeleteAccessPointInput{AccessPointId: &accessPointId}
-	_, err = c.efs.DeleteAccessPointWithContext(ctx, deleteAccessPointInput)
+	_, err = c.efs.DeleteAccessPoint(ctx, deleteAccessPointInput)
 	if err != nil {
 		if isAccessDenied(err) {
 			return ErrAccessDenied
@@ -235,10 +362,13 @@ func (c *cloud) DeleteAccessPoint(ctx context.Context, accessPointId string) (er
 }
 
 func (c *cloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *AccessPoint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
 	describeAPInput := &efs.DescribeAccessPointsInput{
 		AccessPointId: &accessPointId,
 	}
-	res, err := c.efs.DescribeAccessPointsWithContext(ctx, describeAPInput)
+	res, err := c.efs.DescribeAccessPoints(ctx, describeAPInput)
 	if err != nil {
 		if isAccessDenied(err) {
 			return nil, ErrAccessDenied
@@ -264,11 +394,15 @@ func (c *cloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (
 func (c *cloud) findAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions) (accessPoint *AccessPoint, err error) {
 	klog.V(5).Infof("AccessPointOptions to find AP : %+v", accessPointOpts)
 	klog.V(2).Infof("ClientToken to find AP : %s", clientToken)
+
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
 	describeAPInput := &efs.DescribeAccessPointsInput{
 		FileSystemId: &accessPointOpts.FileSystemId,
-		MaxResults:   aws.Int64(1000),
+		MaxResults:   aws.Int32(1000),
 	}
-	res, err := c.efs.DescribeAccessPointsWithContext(ctx, describeAPInput)
+	res, err := c.efs.DescribeAccessPoints(ctx, describeAPInput)
 	if err != nil {
 		if isAccessDenied(err) {
 			return
@@ -281,7 +415,7 @@ func (c *cloud) findAccessPointByClientToken(ctx context.Context, clientToken st
 	}
 	for _, ap := range res.AccessPoints {
 		// check if AP exists with same client token
-		if aws.StringValue(ap.ClientToken) == clientToken {
+		if aws.ToString(ap.ClientToken) == clientToken {
 			return &AccessPoint{
 				AccessPointId:      *ap.AccessPointId,
 				FileSystemId:       *ap.FileSystemId,
@@ -293,41 +427,67 @@ func (c *cloud) findAccessPointByClientToken(ctx context.Context, clientToken st
 	return nil, nil
 }
 
-func (c *cloud) ListAccessPoints(ctx context.Context, fileSystemId string) (accessPoints []*AccessPoint, err error) {
-	describeAPInput := &efs.DescribeAccessPointsInput{
-		FileSystemId: &fileSystemId,
-	}
-	res, err := c.efs.DescribeAccessPointsWithContext(ctx, describeAPInput)
-	if err != nil {
-		if isAccessDenied(err) {
-			return
+func (c *cloud) ListAccessPoints(ctx context.Context, fileSystemId string, filter *ListAccessPointsFilter) (accessPoints []*AccessPoint, err error) {
+	var nextToken *string
+	for {
+		describeAPInput := &efs.DescribeAccessPointsInput{
+			FileSystemId: &fileSystemId,
+			NextToken:    nextToken,
 		}
-		if isFileSystemNotFound(err) {
+
+		opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+		res, describeErr := c.efs.DescribeAccessPoints(opCtx, describeAPInput)
+		cancel()
+		if describeErr != nil {
+			err = describeErr
+			if isAccessDenied(err) {
+				return
+			}
+			if isFileSystemNotFound(err) {
+				return
+			}
+			err = fmt.Errorf("List Access Points failed: %v", err)
 			return
 		}
-		err = fmt.Errorf("List Access Points failed: %v", err)
-		return
-	}
 
-	for _, accessPointDescription := range res.AccessPoints {
-		accessPoint := &AccessPoint{
-			AccessPointId: *accessPointDescription.AccessPointId,
-			FileSystemId:  *accessPointDescription.FileSystemId,
-			PosixUser: &PosixUser{
-				Gid: *accessPointDescription.PosixUser.Gid,
-				Uid: *accessPointDescription.PosixUser.Gid,
-			},
+		for _, accessPointDescription := range res.AccessPoints {
+			if !matchesListAccessPointsFilter(accessPointDescription, filter) {
+				continue
+			}
+
+			var rootDir string
+			if accessPointDescription.RootDirectory != nil {
+				rootDir = aws.ToString(accessPointDescription.RootDirectory.Path)
+			}
+
+			var posixUser *PosixUser
+			if accessPointDescription.PosixUser != nil {
+				posixUser = &PosixUser{
+					Gid: aws.ToInt64(accessPointDescription.PosixUser.Gid),
+					Uid: aws.ToInt64(accessPointDescription.PosixUser.Uid),
+				}
+			}
+
+			accessPoints = append(accessPoints, &AccessPoint{
+				AccessPointId:      aws.ToString(accessPointDescription.AccessPointId),
+				FileSystemId:       aws.ToString(accessPointDescription.FileSystemId),
+				AccessPointRootDir: rootDir,
+				Tags:               tagsToMap(accessPointDescription.Tags),
+				PosixUser:          posixUser,
+			})
 		}
-		accessPoints = append(accessPoints, accessPoint)
+
+		if res.NextToken == nil {
+			break
+		}
+		nextToken = res.NextToken
 	}
 
 	return
 }
 
 func (c *cloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (fs *FileSystem, err error) {
-	describeFsInput := &efs.DescribeFileSystemsInput{FileSystemId: &fileSystemId}
-	klog.V(5).Infof("Calling DescribeFileSystems with input: %+v", *describeFsInput)
-	res, err := c.efs.DescribeFileSystemsWithContext(ctx, describeFsInput)
+	res, err := c.describeFileSystem(ctx, fileSystemId)
 	if err != nil {
 		if isAccessDenied(err) {
 			return nil, ErrAccessDenied
@@ -338,19 +498,19 @@ func (c *cloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (fs
 		return nil, fmt.Errorf("Describe File System failed: %v", err)
 	}
 
-	fileSystems := res.FileSystems
-	if len(fileSystems) == 0 || len(fileSystems) > 1 {
-		return nil, fmt.Errorf("DescribeFileSystem failed. Expected exactly 1 file system in DescribeFileSystem result. However, recevied %d file systems", len(fileSystems))
-	}
 	return &FileSystem{
-		FileSystemId: *res.FileSystems[0].FileSystemId,
+		FileSystemId:         aws.ToString(res.FileSystemId),
+		AvailabilityZoneName: aws.ToString(res.AvailabilityZoneName),
 	}, nil
 }
 
 func (c *cloud) DescribeMountTargets(ctx context.Context, fileSystemId, azName string) (fs *MountTarget, err error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
 	describeMtInput := &efs.DescribeMountTargetsInput{FileSystemId: &fileSystemId}
 	klog.V(5).Infof("Calling DescribeMountTargets with input: %+v", *describeMtInput)
-	res, err := c.efs.DescribeMountTargetsWithContext(ctx, describeMtInput)
+	res, err := c.efs.DescribeMountTargets(ctx, describeMtInput)
 	if err != nil {
 		if isAccessDenied(err) {
 			return nil, ErrAccessDenied
@@ -372,50 +532,320 @@ func (c *cloud) DescribeMountTargets(ctx context.Context, fileSystemId, azName s
 		return nil, fmt.Errorf("No mount target for file system %v is in available state. Please retry in 5 minutes.", fileSystemId)
 	}
 
-	var mountTarget *efs.MountTargetDescription
+	var mountTarget *types.MountTargetDescription
 	if azName != "" {
 		mountTarget = getMountTargetForAz(availableMountTargets, azName)
+
+		// The caller asked for a specific topology (either because the file system is One Zone, or
+		// because the pod was scheduled to a node in a particular AZ). Falling through to the random
+		// pick below would silently ignore that requirement, so if no mount target in azName exists,
+		// fail rather than mount from the wrong zone.
+		if mountTarget == nil {
+			return nil, fmt.Errorf("file system %v has no mount target in AZ %v, which does not satisfy the requested topology", fileSystemId, azName)
+		}
 	}
 
-	// Pick random Mount target from available mount target if azName is not provided.
-	// Or if there is no mount target matching azName
+	// Pick a random mount target if the caller didn't request a specific AZ.
 	if mountTarget == nil {
 		klog.Infof("Picking a random mount target from available mount target")
 		rand.Seed(time.Now().Unix())
-		mountTarget = availableMountTargets[rand.Intn(len(availableMountTargets))]
+		mountTarget = &availableMountTargets[rand.Intn(len(availableMountTargets))]
+	}
+
+	mountTargetId := aws.ToString(mountTarget.MountTargetId)
+	// Security groups are informational (used by the controller to pre-flight reachability before
+	// publishing); a denied or failed lookup here should not block the mount target this call exists
+	// to return, so degrade to an empty list instead of failing the whole call.
+	securityGroups, err := c.describeMountTargetSecurityGroups(ctx, mountTargetId)
+	if err != nil {
+		klog.Warningf("Failed to describe security groups for mount target %v, continuing without them: %v", mountTargetId, err)
+		securityGroups = nil
 	}
 
 	return &MountTarget{
-		AZName:        *mountTarget.AvailabilityZoneName,
-		AZId:          *mountTarget.AvailabilityZoneId,
-		MountTargetId: *mountTarget.MountTargetId,
-		IPAddress:     *mountTarget.IpAddress,
+		AZName:             aws.ToString(mountTarget.AvailabilityZoneName),
+		AZId:               aws.ToString(mountTarget.AvailabilityZoneId),
+		MountTargetId:      mountTargetId,
+		IPAddress:          aws.ToString(mountTarget.IpAddress),
+		DnsName:            mountTargetDnsName(mountTargetId, fileSystemId, c.metadata.GetRegion()),
+		NetworkInterfaceId: aws.ToString(mountTarget.NetworkInterfaceId),
+		SubnetId:           aws.ToString(mountTarget.SubnetId),
+		VpcId:              aws.ToString(mountTarget.VpcId),
+		OwnerId:            aws.ToString(mountTarget.OwnerId),
+		SecurityGroups:     securityGroups,
 	}, nil
 }
 
-func isFileSystemNotFound(err error) bool {
-	if awsErr, ok := err.(awserr.Error); ok {
-		if awsErr.Code() == efs.ErrCodeFileSystemNotFound {
-			return true
+func (c *cloud) describeMountTargetSecurityGroups(ctx context.Context, mountTargetId string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	res, err := c.efs.DescribeMountTargetSecurityGroups(ctx, &efs.DescribeMountTargetSecurityGroupsInput{MountTargetId: &mountTargetId})
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
 		}
+		return nil, err
 	}
-	return false
+	return res.SecurityGroups, nil
 }
 
-func isAccessPointNotFound(err error) bool {
-	if awsErr, ok := err.(awserr.Error); ok {
-		if awsErr.Code() == efs.ErrCodeAccessPointNotFound {
-			return true
+// mountTargetDnsName returns the conventional EFS mount target DNS name for MountTarget.DnsName.
+func mountTargetDnsName(mountTargetId, fileSystemId, region string) string {
+	return fmt.Sprintf("%s.%s.efs.%s.amazonaws.com", mountTargetId, fileSystemId, region)
+}
+
+func (c *cloud) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *FileSystemOptions) (fs *FileSystem, err error) {
+	createFsInput := &efs.CreateFileSystemInput{
+		CreationToken:   &clientToken,
+		Encrypted:       aws.Bool(fileSystemOpts.Encrypted),
+		PerformanceMode: types.PerformanceMode(fileSystemOpts.PerformanceMode),
+		ThroughputMode:  types.ThroughputMode(fileSystemOpts.ThroughputMode),
+		Tags:            parseEfsTags(fileSystemOpts.Tags),
+	}
+	if fileSystemOpts.Encrypted && fileSystemOpts.KmsKeyId != "" {
+		createFsInput.KmsKeyId = &fileSystemOpts.KmsKeyId
+	}
+	if fileSystemOpts.ThroughputMode == string(types.ThroughputModeProvisioned) {
+		createFsInput.ProvisionedThroughputInMibps = &fileSystemOpts.ProvisionedThroughputInMibps
+	}
+
+	klog.V(5).Infof("Calling CreateFileSystem with input: %+v", *createFsInput)
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	res, err := c.efs.CreateFileSystem(opCtx, createFsInput)
+	cancel()
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
 		}
+		if isFileSystemAlreadyExists(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("Failed to create file system: %v", err)
 	}
-	return false
+
+	fileSystemId := aws.ToString(res.FileSystemId)
+	if err := c.waitForFileSystemAvailable(ctx, fileSystemId); err != nil {
+		return nil, fmt.Errorf("File system %v did not become available: %v", fileSystemId, err)
+	}
+
+	return &FileSystem{
+		FileSystemId: fileSystemId,
+	}, nil
 }
 
-func isAccessDenied(err error) bool {
-	if awsErr, ok := err.(awserr.Error); ok {
-		if awsErr.Code() == AccessDeniedException {
-			return true
+func (c *cloud) DeleteFileSystem(ctx context.Context, fileSystemId string) (err error) {
+	deleteFsInput := &efs.DeleteFileSystemInput{FileSystemId: &fileSystemId}
+
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	_, err = c.efs.DeleteFileSystem(opCtx, deleteFsInput)
+	cancel()
+	if err != nil {
+		if isAccessDenied(err) {
+			return ErrAccessDenied
+		}
+		if isFileSystemNotFound(err) {
+			return ErrNotFound
 		}
+		return fmt.Errorf("Failed to delete file system: %v, error: %v", fileSystemId, err)
+	}
+
+	if err := c.waitForFileSystemDeleted(ctx, fileSystemId); err != nil {
+		return fmt.Errorf("File system %v was not deleted: %v", fileSystemId, err)
+	}
+
+	return nil
+}
+
+func (c *cloud) CreateMountTarget(ctx context.Context, fileSystemId string, mountTargetOpts *MountTargetOptions) (mountTarget *MountTarget, err error) {
+	createMtInput := &efs.CreateMountTargetInput{
+		FileSystemId:   &fileSystemId,
+		SubnetId:       &mountTargetOpts.SubnetId,
+		SecurityGroups: mountTargetOpts.SecurityGroups,
+	}
+
+	klog.V(5).Infof("Calling CreateMountTarget with input: %+v", *createMtInput)
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	res, err := c.efs.CreateMountTarget(opCtx, createMtInput)
+	cancel()
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, ErrAccessDenied
+		}
+		if isMountTargetConflict(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("Failed to create mount target for file system %v: %v", fileSystemId, err)
+	}
+
+	mountTargetId := aws.ToString(res.MountTargetId)
+	if err := c.waitForMountTargetAvailable(ctx, mountTargetId); err != nil {
+		return nil, fmt.Errorf("Mount target %v did not become available: %v", mountTargetId, err)
+	}
+
+	return &MountTarget{
+		AZName:        aws.ToString(res.AvailabilityZoneName),
+		AZId:          aws.ToString(res.AvailabilityZoneId),
+		MountTargetId: mountTargetId,
+		IPAddress:     aws.ToString(res.IpAddress),
+	}, nil
+}
+
+func (c *cloud) DeleteMountTarget(ctx context.Context, mountTargetId string) (err error) {
+	deleteMtInput := &efs.DeleteMountTargetInput{MountTargetId: &mountTargetId}
+
+	opCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	_, err = c.efs.DeleteMountTarget(opCtx, deleteMtInput)
+	cancel()
+	if err != nil {
+		if isAccessDenied(err) {
+			return ErrAccessDenied
+		}
+		if isMountTargetNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("Failed to delete mount target: %v, error: %v", mountTargetId, err)
+	}
+
+	if err := c.waitForMountTargetDeleted(ctx, mountTargetId); err != nil {
+		return fmt.Errorf("Mount target %v was not deleted: %v", mountTargetId, err)
+	}
+
+	return nil
+}
+
+// describeFileSystem fetches the raw efs.DescribeFileSystemsOutput entry, used both by the public
+// DescribeFileSystem API and internally by waiters that need the lifecycle state.
+func (c *cloud) describeFileSystem(ctx context.Context, fileSystemId string) (*types.FileSystemDescription, error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	describeFsInput := &efs.DescribeFileSystemsInput{FileSystemId: &fileSystemId}
+	klog.V(5).Infof("Calling DescribeFileSystems with input: %+v", *describeFsInput)
+	res, err := c.efs.DescribeFileSystems(ctx, describeFsInput)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.FileSystems) != 1 {
+		return nil, fmt.Errorf("expected exactly 1 file system, got %d", len(res.FileSystems))
+	}
+	return &res.FileSystems[0], nil
+}
+
+// describeMountTarget fetches the raw types.MountTargetDescription for a single mount target ID.
+func (c *cloud) describeMountTarget(ctx context.Context, mountTargetId string) (*types.MountTargetDescription, error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	res, err := c.efs.DescribeMountTargets(ctx, &efs.DescribeMountTargetsInput{MountTargetId: &mountTargetId})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.MountTargets) != 1 {
+		return nil, fmt.Errorf("expected exactly 1 mount target, got %d", len(res.MountTargets))
+	}
+	return &res.MountTargets[0], nil
+}
+
+func (c *cloud) waitForFileSystemAvailable(ctx context.Context, fileSystemId string) error {
+	return waitForState(ctx, PollCheckInterval, PollCheckTimeout, string(types.LifeCycleStateAvailable), func() (string, error) {
+		fs, err := c.describeFileSystem(ctx, fileSystemId)
+		if err != nil {
+			return "", err
+		}
+		return string(fs.LifeCycleState), nil
+	})
+}
+
+func (c *cloud) waitForFileSystemDeleted(ctx context.Context, fileSystemId string) error {
+	return waitForState(ctx, PollCheckInterval, PollCheckTimeout, string(types.LifeCycleStateDeleted), func() (string, error) {
+		fs, err := c.describeFileSystem(ctx, fileSystemId)
+		if err != nil {
+			if isFileSystemNotFound(err) {
+				return string(types.LifeCycleStateDeleted), nil
+			}
+			return "", err
+		}
+		return string(fs.LifeCycleState), nil
+	})
+}
+
+func (c *cloud) waitForMountTargetAvailable(ctx context.Context, mountTargetId string) error {
+	return waitForState(ctx, PollCheckInterval, PollCheckTimeout, string(types.LifeCycleStateAvailable), func() (string, error) {
+		mt, err := c.describeMountTarget(ctx, mountTargetId)
+		if err != nil {
+			return "", err
+		}
+		return string(mt.LifeCycleState), nil
+	})
+}
+
+func (c *cloud) waitForMountTargetDeleted(ctx context.Context, mountTargetId string) error {
+	return waitForState(ctx, PollCheckInterval, PollCheckTimeout, string(types.LifeCycleStateDeleted), func() (string, error) {
+		mt, err := c.describeMountTarget(ctx, mountTargetId)
+		if err != nil {
+			if isMountTargetNotFound(err) {
+				return string(types.LifeCycleStateDeleted), nil
+			}
+			return "", err
+		}
+		return string(mt.LifeCycleState), nil
+	})
+}
+
+// waitForState polls checkState until it reports desiredState, sleeping pollInterval between
+// attempts, and gives up once pollTimeout elapses. Shared by the file system and mount target
+// create/delete paths, which only differ in which lifecycle state they're waiting for.
+func waitForState(ctx context.Context, pollInterval, pollTimeout time.Duration, desiredState string, checkState func() (string, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	for {
+		state, err := checkState()
+		if err != nil {
+			return err
+		}
+		if state == desiredState {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for state %q, last observed state %q", desiredState, state)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func isFileSystemNotFound(err error) bool {
+	var notFound *types.FileSystemNotFound
+	return errors.As(err, &notFound)
+}
+
+func isAccessPointNotFound(err error) bool {
+	var notFound *types.AccessPointNotFound
+	return errors.As(err, &notFound)
+}
+
+func isFileSystemAlreadyExists(err error) bool {
+	var alreadyExists *types.FileSystemAlreadyExists
+	return errors.As(err, &alreadyExists)
+}
+
+func isMountTargetNotFound(err error) bool {
+	var notFound *types.MountTargetNotFound
+	return errors.As(err, &notFound)
+}
+
+func isMountTargetConflict(err error) bool {
+	var conflict *types.MountTargetConflict
+	return errors.As(err, &conflict)
+}
+
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == AccessDeniedException
 	}
 	return false
 }
@@ -425,12 +855,12 @@ func isDriverBootedInECS() bool {
 	return ecsContainerMetadataUri != ""
 }
 
-func parseEfsTags(tagMap map[string]string) []*efs.Tag {
-	efsTags := []*efs.Tag{}
+func parseEfsTags(tagMap map[string]string) []types.Tag {
+	efsTags := []types.Tag{}
 	for k, v := range tagMap {
 		key := k
 		value := v
-		efsTags = append(efsTags, &efs.Tag{
+		efsTags = append(efsTags, types.Tag{
 			Key:   &key,
 			Value: &value,
 		})
@@ -438,10 +868,35 @@ func parseEfsTags(tagMap map[string]string) []*efs.Tag {
 	return efsTags
 }
 
-func getAvailableMountTargets(mountTargets []*efs.MountTargetDescription) []*efs.MountTargetDescription {
-	availableMountTargets := []*efs.MountTargetDescription{}
+func tagsToMap(tags []types.Tag) map[string]string {
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagMap[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tagMap
+}
+
+func matchesListAccessPointsFilter(ap types.AccessPointDescription, filter *ListAccessPointsFilter) bool {
+	if filter == nil {
+		return true
+	}
+
+	tags := tagsToMap(ap.Tags)
+	if filter.ClusterName != "" && tags[ClusterNameTagKey] != filter.ClusterName {
+		return false
+	}
+	for k, v := range filter.TagFilters {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func getAvailableMountTargets(mountTargets []types.MountTargetDescription) []types.MountTargetDescription {
+	availableMountTargets := []types.MountTargetDescription{}
 	for _, mt := range mountTargets {
-		if *mt.LifeCycleState == "available" {
+		if mt.LifeCycleState == types.LifeCycleStateAvailable {
 			availableMountTargets = append(availableMountTargets, mt)
 		}
 	}
@@ -449,10 +904,10 @@ func getAvailableMountTargets(mountTargets []*efs.MountTargetDescription) []*efs
 	return availableMountTargets
 }
 
-func getMountTargetForAz(mountTargets []*efs.MountTargetDescription, azName string) *efs.MountTargetDescription {
-	for _, mt := range mountTargets {
-		if *mt.AvailabilityZoneName == azName {
-			return mt
+func getMountTargetForAz(mountTargets []types.MountTargetDescription, azName string) *types.MountTargetDescription {
+	for i, mt := range mountTargets {
+		if aws.ToString(mt.AvailabilityZoneName) == azName {
+			return &mountTargets[i]
 		}
 	}
 	klog.Infof("There is no mount target match %v", azName)
@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+)
+
+// mockEfs is a hand-rolled Efs stub. Only the methods a given test configures are expected to be
+// called; any other call panics on the nil embedded Efs or a nil func field, which fails the test.
+type mockEfs struct {
+	Efs
+	describeMountTargetsFn              func(context.Context, *efs.DescribeMountTargetsInput) (*efs.DescribeMountTargetsOutput, error)
+	describeMountTargetSecurityGroupsFn func(context.Context, *efs.DescribeMountTargetSecurityGroupsInput) (*efs.DescribeMountTargetSecurityGroupsOutput, error)
+	describeAccessPointsFn              func(context.Context, *efs.DescribeAccessPointsInput) (*efs.DescribeAccessPointsOutput, error)
+}
+
+func (m *mockEfs) DescribeMountTargets(ctx context.Context, in *efs.DescribeMountTargetsInput, _ ...func(*efs.Options)) (*efs.DescribeMountTargetsOutput, error) {
+	return m.describeMountTargetsFn(ctx, in)
+}
+
+func (m *mockEfs) DescribeMountTargetSecurityGroups(ctx context.Context, in *efs.DescribeMountTargetSecurityGroupsInput, _ ...func(*efs.Options)) (*efs.DescribeMountTargetSecurityGroupsOutput, error) {
+	return m.describeMountTargetSecurityGroupsFn(ctx, in)
+}
+
+func (m *mockEfs) DescribeAccessPoints(ctx context.Context, in *efs.DescribeAccessPointsInput, _ ...func(*efs.Options)) (*efs.DescribeAccessPointsOutput, error) {
+	return m.describeAccessPointsFn(ctx, in)
+}
+
+func mountTargetDescription(mtId, az string, state types.LifeCycleState) types.MountTargetDescription {
+	return types.MountTargetDescription{
+		MountTargetId:        aws.String(mtId),
+		AvailabilityZoneName: aws.String(az),
+		LifeCycleState:       state,
+	}
+}
+
+func TestDescribeMountTargets_RejectsUnsatisfiableAZ(t *testing.T) {
+	c := &cloud{efs: &mockEfs{
+		describeMountTargetsFn: func(_ context.Context, _ *efs.DescribeMountTargetsInput) (*efs.DescribeMountTargetsOutput, error) {
+			return &efs.DescribeMountTargetsOutput{
+				MountTargets: []types.MountTargetDescription{
+					mountTargetDescription("fsmt-1", "us-east-1a", types.LifeCycleStateAvailable),
+					mountTargetDescription("fsmt-2", "us-east-1b", types.LifeCycleStateAvailable),
+				},
+			}, nil
+		},
+	}}
+
+	_, err := c.DescribeMountTargets(context.Background(), "fs-1", "us-east-1c")
+	if err == nil {
+		t.Fatal("expected an error when no mount target exists in the requested AZ, got nil")
+	}
+}
+
+func TestDescribeMountTargets_RejectsOneZoneMismatch(t *testing.T) {
+	c := &cloud{efs: &mockEfs{
+		describeMountTargetsFn: func(_ context.Context, _ *efs.DescribeMountTargetsInput) (*efs.DescribeMountTargetsOutput, error) {
+			return &efs.DescribeMountTargetsOutput{
+				MountTargets: []types.MountTargetDescription{
+					mountTargetDescription("fsmt-1", "us-east-1a", types.LifeCycleStateAvailable),
+				},
+			}, nil
+		},
+	}}
+
+	_, err := c.DescribeMountTargets(context.Background(), "fs-1", "us-east-1b")
+	if err == nil {
+		t.Fatal("expected an error when the One Zone file system's mount target is in a different AZ, got nil")
+	}
+}
+
+func TestWaitForState_ReturnsImmediatelyWhenAlreadyDesired(t *testing.T) {
+	calls := 0
+	err := waitForState(context.Background(), time.Millisecond, time.Second, "available", func() (string, error) {
+		calls++
+		return "available", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected checkState to be called exactly once, got %d", calls)
+	}
+}
+
+func TestWaitForState_PollsUntilDesiredState(t *testing.T) {
+	states := []string{"creating", "creating", "available"}
+	call := 0
+	err := waitForState(context.Background(), time.Millisecond, time.Second, "available", func() (string, error) {
+		state := states[call]
+		call++
+		return state, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if call != len(states) {
+		t.Fatalf("expected checkState to be called %d times, got %d", len(states), call)
+	}
+}
+
+func TestWaitForState_TimesOut(t *testing.T) {
+	err := waitForState(context.Background(), time.Millisecond, 10*time.Millisecond, "available", func() (string, error) {
+		return "creating", nil
+	})
+	if err == nil {
+		t.Fatal("expected a timeout error, got nil")
+	}
+}
+
+func TestWaitForState_PropagatesCheckStateError(t *testing.T) {
+	wantErr := errors.New("describe failed")
+	err := waitForState(context.Background(), time.Millisecond, time.Second, "available", func() (string, error) {
+		return "", wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func accessPointDescription(id string, tags map[string]string) types.AccessPointDescription {
+	var apTags []types.Tag
+	for k, v := range tags {
+		k, v := k, v
+		apTags = append(apTags, types.Tag{Key: &k, Value: &v})
+	}
+	return types.AccessPointDescription{
+		AccessPointId: aws.String(id),
+		FileSystemId:  aws.String("fs-1"),
+		RootDirectory: &types.RootDirectory{Path: aws.String("/" + id)},
+		Tags:          apTags,
+	}
+}
+
+func TestListAccessPoints_FollowsPagination(t *testing.T) {
+	pages := [][]types.AccessPointDescription{
+		{accessPointDescription("fsap-1", nil)},
+		{accessPointDescription("fsap-2", nil)},
+	}
+	call := 0
+	c := &cloud{efs: &mockEfs{
+		describeAccessPointsFn: func(_ context.Context, in *efs.DescribeAccessPointsInput) (*efs.DescribeAccessPointsOutput, error) {
+			out := &efs.DescribeAccessPointsOutput{AccessPoints: pages[call]}
+			call++
+			if call < len(pages) {
+				token := "next"
+				out.NextToken = &token
+			}
+			return out, nil
+		},
+	}}
+
+	aps, err := c.ListAccessPoints(context.Background(), "fs-1", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(aps) != 2 {
+		t.Fatalf("expected 2 access points across both pages, got %d", len(aps))
+	}
+	if call != len(pages) {
+		t.Fatalf("expected DescribeAccessPoints to be called %d times, got %d", len(pages), call)
+	}
+}
+
+func TestListAccessPoints_FiltersByClusterName(t *testing.T) {
+	c := &cloud{efs: &mockEfs{
+		describeAccessPointsFn: func(_ context.Context, _ *efs.DescribeAccessPointsInput) (*efs.DescribeAccessPointsOutput, error) {
+			return &efs.DescribeAccessPointsOutput{
+				AccessPoints: []types.AccessPointDescription{
+					accessPointDescription("fsap-mine", map[string]string{ClusterNameTagKey: "cluster-a"}),
+					accessPointDescription("fsap-other", map[string]string{ClusterNameTagKey: "cluster-b"}),
+				},
+			}, nil
+		},
+	}}
+
+	aps, err := c.ListAccessPoints(context.Background(), "fs-1", &ListAccessPointsFilter{ClusterName: "cluster-a"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(aps) != 1 || aps[0].AccessPointId != "fsap-mine" {
+		t.Fatalf("expected only fsap-mine to survive the cluster filter, got %+v", aps)
+	}
+}